@@ -0,0 +1,172 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"golang.org/x/term"
+)
+
+// ColorMode selects whether output is colorized.
+type ColorMode int
+
+const (
+	ColorAuto ColorMode = iota
+	ColorOn
+	ColorOff
+)
+
+// ParseColorMode parses a --color flag value.
+func ParseColorMode(s string) ColorMode {
+	switch strings.ToLower(s) {
+	case "on", "always", "yes", "true":
+		return ColorOn
+	case "off", "never", "no", "false":
+		return ColorOff
+	default:
+		return ColorAuto
+	}
+}
+
+// ResolveColor decides whether to emit ANSI color codes for out, honoring
+// NO_COLOR and CLICOLOR_FORCE ahead of mode; ColorAuto falls back to an
+// isatty check.
+func ResolveColor(mode ColorMode, out *os.File) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	if _, ok := os.LookupEnv("CLICOLOR_FORCE"); ok {
+		return true
+	}
+
+	switch mode {
+	case ColorOn:
+		return true
+	case ColorOff:
+		return false
+	default:
+		return isatty.IsTerminal(out.Fd()) || isatty.IsCygwinTerminal(out.Fd())
+	}
+}
+
+// Background selects the terminal background prettylog renders against.
+type Background int
+
+const (
+	BackgroundAuto Background = iota
+	BackgroundDark
+	BackgroundLight
+)
+
+// ParseBackground parses "light" or "dark" (case-insensitive) into a
+// Background; any other value yields BackgroundAuto.
+func ParseBackground(s string) Background {
+	switch strings.ToLower(s) {
+	case "light":
+		return BackgroundLight
+	case "dark":
+		return BackgroundDark
+	default:
+		return BackgroundAuto
+	}
+}
+
+// ResolveBackground reports whether bg resolves to a light background.
+// BackgroundAuto first consults COLORFGBG (set by many terminal emulators
+// and multiplexers), then queries the terminal directly via an OSC 11
+// escape sequence with a short read timeout, and finally defaults to dark.
+func ResolveBackground(bg Background, out *os.File) bool {
+	switch bg {
+	case BackgroundLight:
+		return true
+	case BackgroundDark:
+		return false
+	default:
+		if light, ok := lightFromColorFGBG(os.Getenv("COLORFGBG")); ok {
+			return light
+		}
+		if light, ok := lightFromOSC11(out); ok {
+			return light
+		}
+		return false
+	}
+}
+
+// lightFromColorFGBG parses COLORFGBG's "fg;bg" form, where the background
+// color index 7 or 15 (white / light grey) indicates a light background.
+func lightFromColorFGBG(v string) (isLight bool, ok bool) {
+	if v == "" {
+		return false, false
+	}
+	parts := strings.Split(v, ";")
+	n, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return false, false
+	}
+	return n == 7 || n == 15, true
+}
+
+// lightFromOSC11 asks the terminal what its background color is and buckets
+// the reply by perceived luminance. It requires out to be a real TTY, since
+// it puts it in raw mode to read the reply without waiting on a newline.
+func lightFromOSC11(out *os.File) (isLight bool, ok bool) {
+	if !isatty.IsTerminal(out.Fd()) {
+		return false, false
+	}
+
+	fd := int(out.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return false, false
+	}
+	defer term.Restore(fd, state)
+
+	if _, err := fmt.Fprint(out, "\x1b]11;?\x07"); err != nil {
+		return false, false
+	}
+
+	_ = out.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	defer out.SetReadDeadline(time.Time{})
+
+	buf := make([]byte, 64)
+	n, err := out.Read(buf)
+	if err != nil || n == 0 {
+		return false, false
+	}
+
+	return parseOSC11Reply(string(buf[:n]))
+}
+
+// parseOSC11Reply parses a reply like "\x1b]11;rgb:1e1e/1e1e/1e1e\x1b\\" into
+// a light/dark bucket using the standard luminance formula.
+func parseOSC11Reply(reply string) (isLight bool, ok bool) {
+	i := strings.Index(reply, "rgb:")
+	if i == -1 {
+		return false, false
+	}
+
+	channels := strings.SplitN(reply[i+len("rgb:"):], "/", 3)
+	if len(channels) != 3 {
+		return false, false
+	}
+
+	var lum float64
+	weights := [3]float64{0.299, 0.587, 0.114}
+	for idx, ch := range channels {
+		ch = strings.TrimRight(ch, "\x07\x1b\\")
+		if len(ch) > 2 {
+			ch = ch[:2]
+		}
+		v, err := strconv.ParseUint(ch, 16, 16)
+		if err != nil {
+			return false, false
+		}
+		lum += weights[idx] * float64(v) / 255
+	}
+
+	return lum > 0.5, true
+}
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the on-disk shape of $XDG_CONFIG_HOME/prettylog/config.yaml
+// (os.UserConfigDir resolves that variable on Linux, falling back to
+// ~/.config).
+type Config struct {
+	FieldSchema FieldSchema `yaml:"fieldSchema"`
+}
+
+// LoadConfig reads the user's config file, if any. A missing file is not an
+// error: it returns the zero Config so callers can layer it over
+// DefaultFieldSchema with FieldSchema.WithDefaults.
+func LoadConfig() (Config, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return Config{}, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "prettylog", "config.yaml"))
+	if errors.Is(err, os.ErrNotExist) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
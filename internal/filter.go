@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Level is a canonicalized, totally-ordered severity. Structured.Level can
+// hold any of several spellings ("warn", "warning", "WARN") or the name a
+// numeric slog level buckets to; ParseLevel maps all of them onto this
+// order so thresholds compare cleanly.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+	LevelUnknown
+)
+
+// ParseLevel canonicalizes a Structured.Level string into a Level. It also
+// accepts a bare slog-style integer level (e.g. "4", matching the --min-
+// level=4 a user would write by analogy with slog.LevelWarn), bucketing it
+// the same way bucketSlogLevel does.
+func ParseLevel(s string) Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug
+	case "info":
+		return LevelInfo
+	case "warn", "warning":
+		return LevelWarn
+	case "error":
+		return LevelError
+	case "fatal", "panic":
+		return LevelFatal
+	}
+
+	if n, err := strconv.Atoi(s); err == nil {
+		name, _ := bucketSlogLevel(n)
+		return ParseLevel(name)
+	}
+
+	return LevelUnknown
+}
+
+// KVMatcher matches a KV by key, testing its value (stringified) against a
+// regexp. The regexp is unanchored, so it matches anywhere in the value; an
+// exact match needs an anchored pattern such as ^value$.
+type KVMatcher struct {
+	Key   string
+	Value *regexp.Regexp
+}
+
+func (m KVMatcher) matches(data *Structured) bool {
+	for _, kv := range data.KVs {
+		if kv.Key == m.Key {
+			return m.Value.MatchString(fmt.Sprint(kv.Value))
+		}
+	}
+	return false
+}
+
+// Filter decides which events Scan hands to its Sink. The zero Filter
+// matches everything.
+type Filter struct {
+	MinLevel    Level
+	HasMinLevel bool
+	KV          []KVMatcher
+	MsgRegexp   *regexp.Regexp
+	Since       time.Time
+	Until       time.Time
+
+	// DropUnparsed suppresses lines Scan couldn't parse into a Structured
+	// event instead of passing them through unfiltered.
+	DropUnparsed bool
+}
+
+// Match reports whether ev passes the filter. A line with no Structured
+// event (one no Handler recognized) passes through unless DropUnparsed is
+// set, since there's nothing structured left to filter on.
+func (f *Filter) Match(ev *Event) bool {
+	if ev.Structured == nil {
+		return !f.DropUnparsed
+	}
+	data := ev.Structured
+
+	if f.HasMinLevel {
+		if lvl := ParseLevel(data.Level); lvl != LevelUnknown && lvl < f.MinLevel {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && data.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && data.Time.After(f.Until) {
+		return false
+	}
+	if f.MsgRegexp != nil && !f.MsgRegexp.MatchString(data.Msg) {
+		return false
+	}
+	for _, m := range f.KV {
+		if !m.matches(data) {
+			return false
+		}
+	}
+
+	return true
+}
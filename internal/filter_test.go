@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestFilterMatchMinLevel(t *testing.T) {
+	f := &Filter{MinLevel: LevelWarn, HasMinLevel: true}
+
+	tests := []struct {
+		name  string
+		level string
+		want  bool
+	}{
+		{"below threshold is dropped", "info", false},
+		{"at threshold passes", "warn", true},
+		{"above threshold passes", "error", true},
+		{"unrecognized level passes through", "trace", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := &Event{Structured: &Structured{Level: tt.level}}
+			if got := f.Match(ev); got != tt.want {
+				t.Errorf("Match(level=%q) = %v, want %v", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterMatchUnparsedEvent(t *testing.T) {
+	ev := &Event{Raw: "not json"}
+
+	if !(&Filter{}).Match(ev) {
+		t.Error("unparsed event should pass through by default")
+	}
+	if (&Filter{DropUnparsed: true}).Match(ev) {
+		t.Error("unparsed event should be dropped when DropUnparsed is set")
+	}
+}
+
+func TestFilterMatchKV(t *testing.T) {
+	f := &Filter{KV: []KVMatcher{{Key: "user", Value: regexp.MustCompile("^alice$")}}}
+
+	matching := &Event{Structured: &Structured{KVs: []KV{{Key: "user", Value: "alice"}}}}
+	if !f.Match(matching) {
+		t.Error("expected event with matching KV to pass")
+	}
+
+	wrongValue := &Event{Structured: &Structured{KVs: []KV{{Key: "user", Value: "bob"}}}}
+	if f.Match(wrongValue) {
+		t.Error("expected event with non-matching KV value to be dropped")
+	}
+
+	missingKey := &Event{Structured: &Structured{}}
+	if f.Match(missingKey) {
+		t.Error("expected event missing the matched key to be dropped")
+	}
+}
+
+func TestFilterMatchMsgRegexp(t *testing.T) {
+	f := &Filter{MsgRegexp: regexp.MustCompile("failed")}
+
+	if !f.Match(&Event{Structured: &Structured{Msg: "request failed"}}) {
+		t.Error("expected matching message to pass")
+	}
+	if f.Match(&Event{Structured: &Structured{Msg: "request ok"}}) {
+		t.Error("expected non-matching message to be dropped")
+	}
+}
+
+func TestFilterMatchSinceUntil(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	f := &Filter{Since: since, Until: until}
+
+	tests := []struct {
+		name string
+		ts   time.Time
+		want bool
+	}{
+		{"before since is dropped", since.Add(-time.Second), false},
+		{"at since passes", since, true},
+		{"between bounds passes", since.Add(24 * time.Hour), true},
+		{"at until passes", until, true},
+		{"after until is dropped", until.Add(time.Second), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := &Event{Structured: &Structured{Time: tt.ts}}
+			if got := f.Match(ev); got != tt.want {
+				t.Errorf("Match(Time=%v) = %v, want %v", tt.ts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLevelNumeric(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Level
+	}{
+		{"-4", LevelDebug},
+		{"0", LevelInfo},
+		{"4", LevelWarn},
+		{"8", LevelError},
+		{"12", LevelError},
+		{"not-a-level", LevelUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := ParseLevel(tt.in); got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
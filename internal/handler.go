@@ -0,0 +1,199 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Handler recognizes one line format and, if it matches, normalizes the line
+// into out. It reports whether it claimed the line.
+type Handler interface {
+	TryHandle(line []byte, out *Structured) bool
+}
+
+// handlers is the list Scan dispatches every line through, in order. The
+// docker-compose handler goes last since it only fires once the others have
+// had a chance at the unprefixed line.
+var handlers = []Handler{
+	jsonHandler{Schema: DefaultFieldSchema},
+	klogHandler{},
+	logfmtHandler{},
+	dockerComposeHandler{},
+}
+
+// RegisterHandler adds a custom Handler to the front of the dispatch chain,
+// so it gets first refusal on every line Scan reads.
+func RegisterHandler(h Handler) {
+	handlers = append([]Handler{h}, handlers...)
+}
+
+// SetDefaultJSONSchema replaces the FieldSchema the default jsonHandler
+// entry in handlers parses with, for callers (main.go's config loading)
+// that want different key names than DefaultFieldSchema without shadowing
+// it behind a second, unreachable jsonHandler via RegisterHandler.
+func SetDefaultJSONSchema(schema FieldSchema) {
+	for i, h := range handlers {
+		if _, ok := h.(jsonHandler); ok {
+			handlers[i] = jsonHandler{Schema: schema}
+			return
+		}
+	}
+}
+
+func tryHandlers(list []Handler, line []byte, out *Structured) bool {
+	for _, h := range list {
+		if h.TryHandle(line, out) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewJSONHandler builds the JSON Handler with a custom FieldSchema, for
+// callers that want different key names than DefaultFieldSchema without
+// replacing the whole handler chain.
+func NewJSONHandler(schema FieldSchema) Handler {
+	return jsonHandler{Schema: schema}
+}
+
+type jsonHandler struct {
+	Schema FieldSchema
+}
+
+func (h jsonHandler) TryHandle(line []byte, out *Structured) bool {
+	return tryHandleJSON(line, out, h.Schema)
+}
+
+// logfmtHandler parses `key=value key="quoted value"` lines, the format
+// logged by the standard library logfmt encoders and logrus's default text
+// formatter (`level=info msg="started server" addr=:8080`).
+type logfmtHandler struct{}
+
+var logfmtPairRe = regexp.MustCompile(`([a-zA-Z0-9_.]+)=("(?:[^"\\]|\\.)*"|\S+)`)
+
+// logfmtMinCoverage is how much of the (whitespace-trimmed) line the
+// matched key=value pairs must account for before logfmtHandler claims it.
+// Without this, a free-text message that merely contains a k=v-shaped
+// fragment (e.g. a klog line ending in "... generation=2") gets swallowed
+// as a single, mostly-empty logfmt record.
+const logfmtMinCoverage = 0.8
+
+func (logfmtHandler) TryHandle(line []byte, out *Structured) bool {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 {
+		return false
+	}
+
+	matches := logfmtPairRe.FindAllSubmatch(line, -1)
+	if len(matches) == 0 {
+		return false
+	}
+
+	covered := 0
+	for _, m := range matches {
+		covered += len(m[0])
+	}
+	if float64(covered) < logfmtMinCoverage*float64(len(trimmed)) {
+		return false
+	}
+
+	for _, m := range matches {
+		k := string(m[1])
+		v := string(m[2])
+		if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+			v = v[1 : len(v)-1]
+		}
+
+		switch k {
+		case "time", "ts":
+			if t, ok := tryParseTime(v); ok {
+				out.Time = t
+				continue
+			}
+		case "msg", "message":
+			out.Msg = v
+			continue
+		case "level", "lvl":
+			out.Level = v
+			continue
+		}
+		out.KVs = append(out.KVs, KV{Key: k, Value: v})
+	}
+
+	return true
+}
+
+// klogHandler parses the klog/glog line format used by Kubernetes and its
+// ecosystem: `I0920 15:04:05.123456   12345 file.go:42] message`.
+type klogHandler struct{}
+
+var klogRe = regexp.MustCompile(`^([IWEF])(\d{4}) (\d{2}:\d{2}:\d{2}\.\d{6})\s+(\d+)\s+(\S+:\d+)\]\s?(.*)$`)
+
+func (klogHandler) TryHandle(line []byte, out *Structured) bool {
+	m := klogRe.FindSubmatch(line)
+	if m == nil {
+		return false
+	}
+
+	switch string(m[1]) {
+	case "I":
+		out.Level = "info"
+	case "W":
+		out.Level = "warn"
+	case "E":
+		out.Level = "error"
+	case "F":
+		out.Level = "fatal"
+	}
+
+	stamp := fmt.Sprintf("%04d %s %s", time.Now().Year(), m[2], m[3])
+	if t, err := time.Parse("2006 0102 15:04:05.000000", stamp); err == nil {
+		out.Time = t
+	}
+
+	out.Msg = string(m[6])
+	out.KVs = append(out.KVs, KV{Key: "caller", Value: string(m[5])}, KV{Key: "pid", Value: string(m[4])})
+
+	return true
+}
+
+// dockerComposeHandler strips the `service_1 | ` prefix `docker compose
+// logs` adds to every line and reruns the remainder through the live
+// handlers chain (minus itself, to avoid recursing forever on a prefix-like
+// remainder), so JSON or logfmt output from an individual service — using
+// whatever FieldSchema or custom handlers the caller registered — still
+// gets parsed.
+type dockerComposeHandler struct{}
+
+var dockerComposeRe = regexp.MustCompile(`^(\S+)\s+\|\s(.*)$`)
+
+func (dockerComposeHandler) TryHandle(line []byte, out *Structured) bool {
+	m := dockerComposeRe.FindSubmatch(line)
+	if m == nil {
+		return false
+	}
+
+	service, rest := string(m[1]), m[2]
+	if !tryHandlers(innerHandlers(), rest, out) {
+		out.Msg = string(rest)
+	}
+	out.KVs = append(out.KVs, KV{Key: "service", Value: service})
+
+	return true
+}
+
+// innerHandlers returns the live handlers chain with any dockerComposeHandler
+// entries removed, for dockerComposeHandler to rerun against a line's
+// content without recursing into itself.
+func innerHandlers() []Handler {
+	inner := make([]Handler, 0, len(handlers))
+	for _, h := range handlers {
+		if _, ok := h.(dockerComposeHandler); ok {
+			continue
+		}
+		inner = append(inner, h)
+	}
+	return inner
+}
@@ -5,9 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"io"
-	"log"
-	"sort"
-	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -51,10 +48,15 @@ type Palette struct {
 	UnknownLevelColor     *color.Color
 }
 
-func Scan(ctx context.Context, src io.Reader) error {
+// Scan reads log entries from src (one per line, except that a JSON object
+// spanning several lines is one entry), parses each one it can,
+// and hands events that pass filter to sink. A nil filter matches
+// everything. It returns once src is exhausted, ctx is cancelled, or
+// sink.Receive returns an error.
+func Scan(ctx context.Context, src io.Reader, sink Sink, filter *Filter) error {
 	in := bufio.NewScanner(src)
 	in.Buffer(make([]byte, 1024*1024), 1024*1024)
-	in.Split(bufio.ScanLines)
+	in.Split(jsonAwareSplit)
 	var line uint64
 
 	for in.Scan() {
@@ -65,12 +67,16 @@ func Scan(ctx context.Context, src io.Reader) error {
 		ev := Event{Structured: &data, Raw: string(lineData)}
 
 		switch {
-		case TryHandleJson(lineData, &data):
+		case tryHandlers(handlers, lineData, &data):
 		default:
 			ev.Structured = nil
 		}
 
-		if err := PrettyPrint(ctx, &ev); err != nil {
+		if filter != nil && !filter.Match(&ev) {
+			continue
+		}
+
+		if err := sink.Receive(ctx, &ev); err != nil {
 			return err
 		}
 
@@ -84,61 +90,6 @@ func Scan(ctx context.Context, src io.Reader) error {
 	return nil
 }
 
-func PrettyPrint(ctx context.Context, ev *Event) error {
-	if ev.Structured == nil {
-		log.Print(ev.Raw)
-		return nil
-	}
-
-	data := ev.Structured
-	lvl := strings.ToUpper(data.Level)[:imin(4, len(data.Level))]
-	switch strings.ToLower(data.Level) {
-	case "debug":
-		lvl = DefaultPalette.DebugLevelColor.Sprint(lvl)
-	case "info":
-		lvl = DefaultPalette.InfoLevelColor.Sprint(lvl)
-	case "warn", "warning":
-		lvl = DefaultPalette.WarnLevelColor.Sprint(lvl)
-	case "error":
-		lvl = DefaultPalette.ErrorLevelColor.Sprint(lvl)
-	case "fatal", "panic":
-		lvl = DefaultPalette.FatalLevelColor.Sprint(lvl)
-	default:
-		lvl = DefaultPalette.UnknownLevelColor.Sprint(lvl)
-	}
-
-	errorValue := ""
-	caller := ""
-	kvs := make([]string, 0, len(data.KVs))
-	for _, kv := range data.KVs {
-		k, v := kv.Key, kv.Value
-		if k == "stacktrace" {
-			errorValue = kv.Value.(string)
-			continue
-		}
-		if k == "caller" {
-			caller = kv.Value.(string)
-			continue
-		}
-		kstr := DefaultPalette.KeyColor.Sprint(k)
-		vstr := DefaultPalette.ValColor.Sprint(v)
-		kvs = append(kvs, kstr+"="+vstr)
-	}
-	sort.Strings(kvs)
-
-	log.Printf("%s [%s] %s\t[%s] %s",
-		DefaultPalette.TimeColor.Sprint(data.Time.Format(timeFormat)), lvl, data.Msg, DefaultPalette.CallerColor.Sprint(caller), strings.Join(kvs, "\t"))
-	if errorValue != "" {
-		log.Print(DefaultPalette.ErrorLevelColor.Sprint("╭────────────────Traceback──────────"))
-		for _, line := range strings.Split(errorValue, "\n") {
-			log.Print(DefaultPalette.ErrorLevelColor.Sprint("│") + line)
-		}
-		log.Print(DefaultPalette.ErrorLevelColor.Sprint("╰───────────────────────────────────"))
-	}
-
-	return nil
-}
-
 type Event struct {
 	Structured *Structured
 	Raw        string
@@ -153,27 +104,52 @@ type Structured struct {
 	Time  time.Time
 	Msg   string
 	Level string
-	KVs   []KV
+	// LevelNum holds the raw numeric level when the source uses slog-style
+	// integer levels. LevelDelta is that number's offset from its bucket's
+	// base (e.g. slog's 12 buckets to "error" with a delta of 4).
+	LevelNum   *int
+	LevelDelta int
+	KVs        []KV
 }
 
+// TryHandleJson parses d as JSON using DefaultFieldSchema. jsonHandler wraps
+// tryHandleJSON directly so a custom schema can be plugged in instead.
 func TryHandleJson(d []byte, out *Structured) bool {
+	return tryHandleJSON(d, out, DefaultFieldSchema)
+}
+
+func tryHandleJSON(d []byte, out *Structured, schema FieldSchema) bool {
 	raw := make(map[string]interface{})
 	err := json.Unmarshal(d, &raw)
 	if err != nil {
 		return false
 	}
 
-	if time, ok := tryParseTime(raw["ts"]); ok {
-		out.Time = time
-		delete(raw, "ts")
+	if v, k, ok := firstKey(raw, schema.TimeKeys); ok {
+		if t, ok := tryParseTime(v); ok {
+			out.Time = t
+			delete(raw, k)
+		}
 	}
-	if msg, ok := raw["msg"].(string); ok {
-		out.Msg = msg
-		delete(raw, "msg")
+	if v, k, ok := firstKey(raw, schema.MsgKeys); ok {
+		if msg, ok := v.(string); ok {
+			out.Msg = msg
+			delete(raw, k)
+		}
 	}
-	if level, ok := raw["level"].(string); ok {
-		out.Level = level
-		delete(raw, "level")
+	if v, k, ok := firstKey(raw, schema.LevelKeys); ok {
+		switch level := v.(type) {
+		case string:
+			out.Level = level
+			delete(raw, k)
+		case float64:
+			n := int(level)
+			name, delta := bucketSlogLevel(n)
+			out.Level = name
+			out.LevelNum = &n
+			out.LevelDelta = delta
+			delete(raw, k)
+		}
 	}
 
 	for k, v := range raw {
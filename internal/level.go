@@ -0,0 +1,38 @@
+package internal
+
+import "strings"
+
+// bucketSlogLevel maps a slog-style integer level onto one of the four
+// canonical severities and the offset from that bucket's base, mirroring
+// slog.Level's Debug=-4/Info=0/Warn=4/Error=8 boundaries. An intermediate
+// value like 12 buckets to "error" with a delta of 4 ("ERR+4").
+func bucketSlogLevel(n int) (level string, delta int) {
+	switch {
+	case n < 0:
+		return "debug", n + 4
+	case n < 4:
+		return "info", n
+	case n < 8:
+		return "warn", n - 4
+	default:
+		return "error", n - 8
+	}
+}
+
+// slogLevelAbbr renders a bucketSlogLevel name as the fixed 3-letter code
+// (DBG/INF/WRN/ERR) the tint library uses for slog levels, as opposed to
+// the generic 4-char truncation StdioSink uses for string levels.
+func slogLevelAbbr(level string) string {
+	switch level {
+	case "debug":
+		return "DBG"
+	case "info":
+		return "INF"
+	case "warn":
+		return "WRN"
+	case "error":
+		return "ERR"
+	default:
+		return strings.ToUpper(level)
+	}
+}
@@ -0,0 +1,64 @@
+package internal
+
+// FieldSchema configures which raw key names map to each semantic field.
+// Structured logging libraries disagree on names: zap uses ts/msg/level,
+// slog uses time/msg/level/source, logrus uses time/message/level, bunyan
+// uses time/msg/level/v. The first key in each list present on a line wins.
+type FieldSchema struct {
+	TimeKeys       []string `yaml:"timeKeys" json:"timeKeys"`
+	MsgKeys        []string `yaml:"msgKeys" json:"msgKeys"`
+	LevelKeys      []string `yaml:"levelKeys" json:"levelKeys"`
+	CallerKeys     []string `yaml:"callerKeys" json:"callerKeys"`
+	StacktraceKeys []string `yaml:"stacktraceKeys" json:"stacktraceKeys"`
+}
+
+// DefaultFieldSchema covers zap, slog, logrus and bunyan out of the box.
+var DefaultFieldSchema = FieldSchema{
+	TimeKeys:       []string{"ts", "time", "timestamp", "@timestamp"},
+	MsgKeys:        []string{"msg", "message"},
+	LevelKeys:      []string{"level", "lvl", "severity"},
+	CallerKeys:     []string{"caller", "source"},
+	StacktraceKeys: []string{"stacktrace", "stack"},
+}
+
+// WithDefaults fills any unset field list with DefaultFieldSchema's, so a
+// user's config.yaml only needs to override what it wants to change.
+func (s FieldSchema) WithDefaults() FieldSchema {
+	if len(s.TimeKeys) == 0 {
+		s.TimeKeys = DefaultFieldSchema.TimeKeys
+	}
+	if len(s.MsgKeys) == 0 {
+		s.MsgKeys = DefaultFieldSchema.MsgKeys
+	}
+	if len(s.LevelKeys) == 0 {
+		s.LevelKeys = DefaultFieldSchema.LevelKeys
+	}
+	if len(s.CallerKeys) == 0 {
+		s.CallerKeys = DefaultFieldSchema.CallerKeys
+	}
+	if len(s.StacktraceKeys) == 0 {
+		s.StacktraceKeys = DefaultFieldSchema.StacktraceKeys
+	}
+	return s
+}
+
+// firstKey returns the value and name of the first key from keys present in
+// raw.
+func firstKey(raw map[string]interface{}, keys []string) (interface{}, string, bool) {
+	for _, k := range keys {
+		if v, ok := raw[k]; ok {
+			return v, k, true
+		}
+	}
+	return nil, "", false
+}
+
+// contains reports whether s is present in list.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
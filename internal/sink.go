@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Sink receives parsed log events and renders or forwards them. Scan feeds
+// every event that passes its Filter to a Sink; a process exits once the
+// Sink is done with it.
+type Sink interface {
+	Receive(ctx context.Context, ev *Event) error
+	Close() error
+}
+
+// StdioSink renders events as human-friendly colorized text, the original
+// behaviour of this tool before Sink existed.
+type StdioSink struct {
+	Writer     io.Writer
+	Palette    Palette
+	Schema     FieldSchema
+	TimeFormat string
+	LightBg    bool
+	SortKeys   bool
+}
+
+// NewStdioSink builds a StdioSink with this tool's historical defaults.
+func NewStdioSink(w io.Writer) *StdioSink {
+	return &StdioSink{
+		Writer:     w,
+		Palette:    DefaultPalette,
+		Schema:     DefaultFieldSchema,
+		TimeFormat: timeFormat,
+		SortKeys:   true,
+	}
+}
+
+func (s *StdioSink) Receive(_ context.Context, ev *Event) error {
+	if ev.Structured == nil {
+		_, err := fmt.Fprintln(s.Writer, ev.Raw)
+		return err
+	}
+
+	data := ev.Structured
+	var abbr string
+	if data.LevelNum != nil {
+		abbr = slogLevelAbbr(data.Level)
+		if data.LevelDelta != 0 {
+			abbr = fmt.Sprintf("%s%+d", abbr, data.LevelDelta)
+		}
+	} else {
+		abbr = strings.ToUpper(data.Level)[:imin(4, len(data.Level))]
+	}
+
+	var levelColor *color.Color
+	switch strings.ToLower(data.Level) {
+	case "debug":
+		levelColor = s.Palette.DebugLevelColor
+	case "info":
+		levelColor = s.Palette.InfoLevelColor
+	case "warn", "warning":
+		levelColor = s.Palette.WarnLevelColor
+	case "error":
+		levelColor = s.Palette.ErrorLevelColor
+	case "fatal", "panic":
+		levelColor = s.Palette.FatalLevelColor
+	default:
+		levelColor = s.Palette.UnknownLevelColor
+	}
+	lvl := levelColor.Sprint(abbr)
+
+	msgColor, absentColor := s.Palette.MsgDarkBgColor, s.Palette.MsgAbsentDarkBgColor
+	if s.LightBg {
+		msgColor, absentColor = s.Palette.MsgLightBgColor, s.Palette.MsgAbsentLightBgColor
+	}
+	msg := msgColor.Sprint(data.Msg)
+	if data.Msg == "" {
+		msg = absentColor.Sprint("<no message>")
+	}
+
+	errorValue := ""
+	caller := ""
+	kvs := make([]string, 0, len(data.KVs))
+	for _, kv := range data.KVs {
+		k, v := kv.Key, kv.Value
+		if contains(s.Schema.StacktraceKeys, k) {
+			errorValue = stringifyValue(v)
+			continue
+		}
+		if contains(s.Schema.CallerKeys, k) {
+			caller = stringifyValue(v)
+			continue
+		}
+		kstr := s.Palette.KeyColor.Sprint(k)
+		vstr := s.Palette.ValColor.Sprint(v)
+		kvs = append(kvs, kstr+"="+vstr)
+	}
+	if s.SortKeys {
+		sort.Strings(kvs)
+	}
+
+	if _, err := fmt.Fprintf(s.Writer, "%s [%s] %s\t[%s] %s\n",
+		s.Palette.TimeColor.Sprint(data.Time.Format(s.TimeFormat)), lvl, msg, s.Palette.CallerColor.Sprint(caller), strings.Join(kvs, "\t")); err != nil {
+		return err
+	}
+
+	if errorValue != "" {
+		fmt.Fprintln(s.Writer, s.Palette.ErrorLevelColor.Sprint("╭────────────────Traceback──────────"))
+		for _, line := range strings.Split(errorValue, "\n") {
+			fmt.Fprintln(s.Writer, s.Palette.ErrorLevelColor.Sprint("│")+line)
+		}
+		fmt.Fprintln(s.Writer, s.Palette.ErrorLevelColor.Sprint("╰───────────────────────────────────"))
+	}
+
+	return nil
+}
+
+func (s *StdioSink) Close() error {
+	return nil
+}
+
+// stringifyValue renders a KV value as text. Most sources put a string
+// here, but slog's AddSource option nests an object ({"function":...,
+// "file":...,"line":...}) under the source key, so callers can't assume the
+// type.
+func stringifyValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestStdioSinkReceiveLevelAbbreviation(t *testing.T) {
+	color.NoColor = true
+	defer func() { color.NoColor = false }()
+
+	tests := []struct {
+		name     string
+		data     Structured
+		wantAbbr string
+	}{
+		{"string level truncates to four chars", Structured{Level: "error"}, "ERRO"},
+		{"numeric error with delta uses three-letter code", Structured{Level: "error", LevelNum: intPtr(12), LevelDelta: 4}, "ERR+4"},
+		{"numeric warn with delta", Structured{Level: "warn", LevelNum: intPtr(6), LevelDelta: 2}, "WRN+2"},
+		{"numeric level with zero delta has no suffix", Structured{Level: "info", LevelNum: intPtr(0), LevelDelta: 0}, "INF"},
+		{"numeric debug with negative delta", Structured{Level: "debug", LevelNum: intPtr(-8), LevelDelta: -4}, "DBG-4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			sink := NewStdioSink(&buf)
+			ev := &Event{Structured: &tt.data}
+			if err := sink.Receive(context.Background(), ev); err != nil {
+				t.Fatalf("Receive: %v", err)
+			}
+			if want := "[" + tt.wantAbbr + "]"; !strings.Contains(buf.String(), want) {
+				t.Errorf("output %q does not contain level tag %s", buf.String(), want)
+			}
+		})
+	}
+}
+
+// TestStdioSinkReceiveNonStringCallerDoesNotPanic guards against a
+// regression: slog's AddSource option nests an object under the source
+// key, not a string.
+func TestStdioSinkReceiveNonStringCallerDoesNotPanic(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewStdioSink(&buf)
+	data := Structured{
+		Level: "info",
+		Msg:   "hello",
+		KVs: []KV{
+			{Key: "source", Value: map[string]interface{}{"file": "x.go", "line": 13}},
+		},
+	}
+	ev := &Event{Structured: &data}
+
+	if err := sink.Receive(context.Background(), ev); err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+)
+
+// jsonAwareSplit is a bufio.SplitFunc that keeps a JSON object together even
+// when it legitimately spans multiple lines — a Go %+v error's stacktrace
+// field containing a literal newline, or JSON a producer pretty-printed
+// across lines. At the start of a token it peeks the first non-whitespace
+// byte: if it's '{', it accumulates bytes (tracking brace depth with a
+// minimal string-aware scanner that respects `"` and `\"` escapes) until
+// depth returns to zero; otherwise it falls back to bufio.ScanLines.
+func jsonAwareSplit(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	start := 0
+	for start < len(data) && isJSONSpace(data[start]) {
+		start++
+	}
+
+	if start == len(data) {
+		if atEOF {
+			return len(data), nil, nil
+		}
+		return 0, nil, nil
+	}
+
+	if data[start] != '{' {
+		return bufio.ScanLines(data, atEOF)
+	}
+
+	depth := 0
+	inString := false
+	escaped := false
+	for i := start; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1, escapeRawNewlines(data[start : i+1]), nil
+			}
+		}
+	}
+
+	if atEOF {
+		return 0, nil, fmt.Errorf("prettylog: unterminated JSON object (depth %d) at EOF", depth)
+	}
+
+	return 0, nil, nil
+}
+
+// escapeRawNewlines escapes literal newline, carriage return and tab bytes
+// that appear inside JSON string literals, so a log entry whose message was
+// written with a raw newline instead of the `\n` escape (common in stack
+// traces from Go's %+v) still parses as valid JSON.
+func escapeRawNewlines(b []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(b))
+
+	inString := false
+	escaped := false
+	for _, c := range b {
+		if !inString {
+			if c == '"' {
+				inString = true
+			}
+			out.WriteByte(c)
+			continue
+		}
+
+		if escaped {
+			escaped = false
+			out.WriteByte(c)
+			continue
+		}
+
+		switch c {
+		case '\\':
+			escaped = true
+			out.WriteByte(c)
+		case '"':
+			inString = false
+			out.WriteByte(c)
+		case '\n':
+			out.WriteString(`\n`)
+		case '\r':
+			out.WriteString(`\r`)
+		case '\t':
+			out.WriteString(`\t`)
+		default:
+			out.WriteByte(c)
+		}
+	}
+
+	return out.Bytes()
+}
+
+func isJSONSpace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\r', '\n':
+		return true
+	default:
+		return false
+	}
+}
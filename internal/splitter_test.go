@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func scanAll(t *testing.T, input string) []string {
+	t.Helper()
+
+	sc := bufio.NewScanner(strings.NewReader(input))
+	sc.Buffer(make([]byte, 1024*1024), 1024*1024)
+	sc.Split(jsonAwareSplit)
+
+	var tokens []string
+	for sc.Scan() {
+		tokens = append(tokens, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	return tokens
+}
+
+func TestJSONAwareSplitPlainLines(t *testing.T) {
+	tokens := scanAll(t, "plain line one\nplain line two\n")
+	want := []string{"plain line one", "plain line two"}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %d tokens, want %d: %q", len(tokens), len(want), tokens)
+	}
+	for i, w := range want {
+		if tokens[i] != w {
+			t.Errorf("token %d = %q, want %q", i, tokens[i], w)
+		}
+	}
+}
+
+func TestJSONAwareSplitSingleLineJSON(t *testing.T) {
+	tokens := scanAll(t, "{\"a\":1}\n{\"b\":2}\n")
+	want := []string{`{"a":1}`, `{"b":2}`}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %d tokens, want %d: %q", len(tokens), len(want), tokens)
+	}
+	for i, w := range want {
+		if tokens[i] != w {
+			t.Errorf("token %d = %q, want %q", i, tokens[i], w)
+		}
+	}
+}
+
+// TestJSONAwareSplitEmbeddedNewlineInStacktrace mirrors the output of Go's
+// zap.Error(err): a JSON object whose "stacktrace" field contains literal
+// newlines rather than the `\n` escape.
+func TestJSONAwareSplitEmbeddedNewlineInStacktrace(t *testing.T) {
+	input := "{\"level\":\"error\",\"msg\":\"boom\",\"stacktrace\":\"main.foo\n\tmain.go:10\n\tmain.bar\"}\n" +
+		"{\"level\":\"info\",\"msg\":\"after\"}\n"
+
+	tokens := scanAll(t, input)
+	if len(tokens) != 2 {
+		t.Fatalf("got %d tokens, want 2: %q", len(tokens), tokens)
+	}
+
+	var data Structured
+	if !TryHandleJson([]byte(tokens[0]), &data) {
+		t.Fatalf("TryHandleJson rejected first token: %q", tokens[0])
+	}
+	if data.Msg != "boom" {
+		t.Errorf("Msg = %q, want %q", data.Msg, "boom")
+	}
+
+	wantStacktrace := "main.foo\n\tmain.go:10\n\tmain.bar"
+	var gotStacktrace string
+	var found bool
+	for _, kv := range data.KVs {
+		if kv.Key == "stacktrace" {
+			found = true
+			gotStacktrace, _ = kv.Value.(string)
+		}
+	}
+	if !found {
+		t.Fatalf("stacktrace key missing from KVs: %+v", data.KVs)
+	}
+	if gotStacktrace != wantStacktrace {
+		t.Errorf("stacktrace = %q, want %q", gotStacktrace, wantStacktrace)
+	}
+
+	if tokens[1] != `{"level":"info","msg":"after"}` {
+		t.Errorf("second token = %q, want unaffected by the first", tokens[1])
+	}
+}
+
+func TestJSONAwareSplitPrettyPrintedJSON(t *testing.T) {
+	input := "{\n  \"level\": \"info\",\n  \"msg\": \"pretty\"\n}\n{\"level\":\"warn\",\"msg\":\"next\"}\n"
+
+	tokens := scanAll(t, input)
+	if len(tokens) != 2 {
+		t.Fatalf("got %d tokens, want 2: %q", len(tokens), tokens)
+	}
+
+	var data Structured
+	if !TryHandleJson([]byte(tokens[0]), &data) {
+		t.Fatalf("TryHandleJson rejected pretty-printed token: %q", tokens[0])
+	}
+	if data.Msg != "pretty" {
+		t.Errorf("Msg = %q, want %q", data.Msg, "pretty")
+	}
+}
+
+func TestJSONAwareSplitUnterminatedJSONAtEOF(t *testing.T) {
+	sc := bufio.NewScanner(strings.NewReader(`{"a":1`))
+	sc.Split(jsonAwareSplit)
+
+	for sc.Scan() {
+	}
+	if err := sc.Err(); err == nil {
+		t.Fatal("expected an error for an unterminated JSON object at EOF")
+	}
+}
@@ -2,19 +2,105 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/fatih/color"
+	"github.com/mattn/go-colorable"
 	"github.com/senior-sigan/prettylog/internal"
 )
 
+// kvFlags collects repeated -match key=value flags into KVMatchers.
+type kvFlags []internal.KVMatcher
+
+func (f *kvFlags) String() string { return fmt.Sprint(*f) }
+
+func (f *kvFlags) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("-match must be key=value, got %q", value)
+	}
+	re, err := regexp.Compile(val)
+	if err != nil {
+		re = regexp.MustCompile(regexp.QuoteMeta(val))
+	}
+	*f = append(*f, internal.KVMatcher{Key: key, Value: re})
+	return nil
+}
+
 func main() {
 	log.SetFlags(0)
 
+	minLevel := flag.String("min-level", "", "suppress events below this level (debug|info|warn|error|fatal)")
+	grep := flag.String("grep", "", "only show events whose message matches this regexp")
+	dropUnparsed := flag.Bool("drop-unparsed", false, "drop lines that couldn't be parsed instead of passing them through")
+	since := flag.String("since", "", "only show events at or after this RFC3339 time")
+	until := flag.String("until", "", "only show events at or before this RFC3339 time")
+	colorFlag := flag.String("color", "auto", "colorize output: auto, on, off")
+	lightBg := flag.Bool("light-bg", false, "assume a light terminal background")
+	darkBg := flag.Bool("dark-bg", false, "assume a dark terminal background")
+	var match kvFlags
+	flag.Var(&match, "match", "only show events with this key=value (value is a regexp); may be repeated")
+	flag.Parse()
+
+	filter := &internal.Filter{KV: match, DropUnparsed: *dropUnparsed}
+	if *minLevel != "" {
+		filter.MinLevel = internal.ParseLevel(*minLevel)
+		filter.HasMinLevel = true
+	}
+	if *grep != "" {
+		re, err := regexp.Compile(*grep)
+		if err != nil {
+			log.Fatalf("invalid -grep pattern: %v", err)
+		}
+		filter.MsgRegexp = re
+	}
+	if *since != "" {
+		t, err := time.Parse(time.RFC3339, *since)
+		if err != nil {
+			log.Fatalf("invalid -since time: %v", err)
+		}
+		filter.Since = t
+	}
+	if *until != "" {
+		t, err := time.Parse(time.RFC3339, *until)
+		if err != nil {
+			log.Fatalf("invalid -until time: %v", err)
+		}
+		filter.Until = t
+	}
+
+	color.NoColor = !internal.ResolveColor(internal.ParseColorMode(*colorFlag), os.Stdout)
+
+	background := internal.BackgroundAuto
+	switch {
+	case *lightBg:
+		background = internal.BackgroundLight
+	case *darkBg:
+		background = internal.BackgroundDark
+	}
+
 	log.Printf("reading stdin...")
 
+	cfg, err := internal.LoadConfig()
+	if err != nil {
+		log.Fatalf("loading config: %v", err)
+	}
+	schema := cfg.FieldSchema.WithDefaults()
+	internal.SetDefaultJSONSchema(schema)
+
+	sink := internal.NewStdioSink(colorable.NewColorableStdout())
+	sink.Schema = schema
+	sink.LightBg = internal.ResolveBackground(background, os.Stdout)
+	defer sink.Close()
+
 	ctx := context.Background()
-	if err := internal.Scan(ctx, os.Stdin); err != nil {
+	if err := internal.Scan(ctx, os.Stdin, sink, filter); err != nil {
 		log.Fatalf("scanning caught an error: %v", err)
 	}
 }